@@ -0,0 +1,14 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fix provides the building blocks for writing "gofix"-style
+// source rewriters: programs that walk a Go AST, detect an outdated
+// API pattern and rewrite it in place.
+//
+// A rewriter registers one or more Fix values with Register and then
+// hands the resulting list to Main, which implements the standard
+// gofix command-line driver (reading files or stdin, writing files or
+// diffs, restricting the fixes applied by name, and optionally skipping
+// files that do not parse).
+package fix
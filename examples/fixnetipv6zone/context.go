@@ -0,0 +1,25 @@
+// Copyright 2016 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/tmc/fix"
+	"go/ast"
+)
+
+func init() {
+	fix.Register(contextFix)
+}
+
+var contextFix = fix.Fix{
+	Name: "context",
+	Date: "2016-09-09",
+	F:    ctxfix,
+	Desc: `Change imports of golang.org/x/net/context to context`,
+}
+
+func ctxfix(f *ast.File) bool {
+	return fix.RewriteImport(f, "golang.org/x/net/context", "context")
+}
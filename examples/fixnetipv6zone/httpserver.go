@@ -0,0 +1,142 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/tmc/fix"
+	"go/ast"
+	"go/token"
+)
+
+func init() {
+	fix.Register(httpserverFix)
+}
+
+var httpserverFix = fix.Fix{
+	Name: "httpserver",
+	Date: "2011-03-21",
+	F:    httpserver,
+	Desc: `Adapt http.Handler implementations to the ResponseWriter-based net/http API.
+
+ServeHTTP(*http.Conn, *http.Request) becomes
+ServeHTTP(http.ResponseWriter, *http.Request), and the Conn-only methods
+SetHeader, RemoteAddr, and UsingTLS are rewritten to their
+ResponseWriter/Request equivalents.
+`,
+}
+
+func httpserver(f *ast.File) bool {
+	if !fix.Imports(f, "http") && !fix.Imports(f, "net/http") {
+		return false
+	}
+
+	fixed := false
+	fix.Walk(f, func(n interface{}) {
+		fn, ok := n.(*ast.FuncDecl)
+		if !ok {
+			return
+		}
+		connName, reqName, ok := fixServeHTTPSig(fn)
+		if !ok {
+			return
+		}
+		fixed = true
+		if fn.Body != nil {
+			rewriteConnCalls(fn.Body, connName, reqName)
+		}
+	})
+	return fixed
+}
+
+// fixServeHTTPSig rewrites fn in place if it is a ServeHTTP method with the
+// legacy (*http.Conn, *http.Request) signature, changing the first
+// parameter's type to http.ResponseWriter. It returns the names the Conn
+// and Request parameters were declared under.
+func fixServeHTTPSig(fn *ast.FuncDecl) (connName, reqName string, ok bool) {
+	if fn.Recv == nil || fn.Name.Name != "ServeHTTP" || fn.Type.Params == nil {
+		return "", "", false
+	}
+	params := fn.Type.Params.List
+	if len(params) != 2 {
+		return "", "", false
+	}
+	connField, reqField := params[0], params[1]
+	if !isPtrSelector(connField.Type, "http", "Conn") ||
+		!isPtrSelector(reqField.Type, "http", "Request") {
+		return "", "", false
+	}
+	if len(connField.Names) != 1 || len(reqField.Names) != 1 {
+		return "", "", false
+	}
+
+	connField.Type = &ast.SelectorExpr{X: ast.NewIdent("http"), Sel: ast.NewIdent("ResponseWriter")}
+	return connField.Names[0].Name, reqField.Names[0].Name, true
+}
+
+// isPtrSelector reports whether expr is of the form *pkg.name.
+func isPtrSelector(expr ast.Expr, pkg, name string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := star.X.(*ast.SelectorExpr)
+	if !ok || sel.Sel == nil {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg && sel.Sel.Name == name
+}
+
+// rewriteConnCalls replaces the legacy http.Conn methods SetHeader,
+// RemoteAddr, and UsingTLS used on connName within body with their
+// ResponseWriter/Request equivalents on connName and reqName.
+func rewriteConnCalls(body *ast.BlockStmt, connName, reqName string) {
+	fix.Walk(body, func(n interface{}) {
+		ep, ok := n.(*ast.Expr)
+		if !ok {
+			return
+		}
+		call, ok := (*ep).(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		se, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || se.Sel == nil {
+			return
+		}
+		id, ok := se.X.(*ast.Ident)
+		if !ok || id.Name != connName {
+			return
+		}
+
+		switch se.Sel.Name {
+		case "SetHeader":
+			if len(call.Args) != 2 {
+				return
+			}
+			*ep = &ast.CallExpr{
+				Fun: &ast.SelectorExpr{
+					X:   &ast.CallExpr{Fun: &ast.SelectorExpr{X: ast.NewIdent(connName), Sel: ast.NewIdent("Header")}},
+					Sel: ast.NewIdent("Set"),
+				},
+				Args: call.Args,
+			}
+		case "RemoteAddr":
+			if len(call.Args) != 0 {
+				return
+			}
+			*ep = &ast.SelectorExpr{X: ast.NewIdent(reqName), Sel: ast.NewIdent("RemoteAddr")}
+		case "UsingTLS":
+			if len(call.Args) != 0 {
+				return
+			}
+			*ep = &ast.BinaryExpr{
+				X:  &ast.SelectorExpr{X: ast.NewIdent(reqName), Sel: ast.NewIdent("TLS")},
+				Op: token.NEQ,
+				Y:  ast.NewIdent("nil"),
+			}
+		}
+	})
+}
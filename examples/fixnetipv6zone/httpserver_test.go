@@ -0,0 +1,64 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+func init() {
+	addTestCases(httpserverTests, httpserver)
+}
+
+var httpserverTests = []testCase{
+	{
+		Name: "httpserver.0",
+		In: `package main
+
+import "http"
+
+type myHandler struct{}
+
+func (h *myHandler) ServeHTTP(c *http.Conn, req *http.Request) {
+	c.SetHeader("Content-Type", "text/plain")
+	addr := c.RemoteAddr()
+	secure := c.UsingTLS()
+	_ = addr
+	_ = secure
+}
+`,
+		Out: `package main
+
+import "http"
+
+type myHandler struct{}
+
+func (h *myHandler) ServeHTTP(c http.ResponseWriter, req *http.Request) {
+	c.Header().Set("Content-Type", "text/plain")
+	addr := req.RemoteAddr
+	secure := req.TLS != nil
+	_ = addr
+	_ = secure
+}
+`,
+	},
+	{
+		// A bodyless declaration (e.g. implemented in assembly) has no
+		// statements to rewrite, but its signature must still be fixed.
+		Name: "httpserver.1",
+		In: `package main
+
+import "http"
+
+type myHandler struct{}
+
+func (h *myHandler) ServeHTTP(c *http.Conn, req *http.Request)
+`,
+		Out: `package main
+
+import "http"
+
+type myHandler struct{}
+
+func (h *myHandler) ServeHTTP(c http.ResponseWriter, req *http.Request)
+`,
+	},
+}
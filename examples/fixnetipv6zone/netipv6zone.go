@@ -14,17 +14,18 @@ func init() {
 }
 
 var netipv6zoneFix = fix.Fix{
-	"netipv6zone",
-	"2012-11-26",
-	netipv6zone,
-	`Adapt element key to IPAddr, UDPAddr or TCPAddr composite literals.
+	Name: "netipv6zone",
+	Date: "2012-11-26",
+	F:    netipv6zone,
+	Desc: `Adapt element key to IPAddr, UDPAddr or TCPAddr composite literals.
 
 https://codereview.appspot.com/6849045/
 `,
 }
 
 func netipv6zone(f *ast.File) bool {
-	if !fix.Imports(f, "net") {
+	netObj := fix.PackageObject(f, "net")
+	if netObj == nil {
 		return false
 	}
 
@@ -38,7 +39,8 @@ func netipv6zone(f *ast.File) bool {
 		if !ok {
 			return
 		}
-		if !fix.IsTopName(se.X, "net") || se.Sel == nil {
+		id, ok := se.X.(*ast.Ident)
+		if !ok || !fix.RefersTo(f, id, netObj) || se.Sel == nil {
 			return
 		}
 		switch ss := se.Sel.String(); ss {
@@ -71,6 +71,34 @@ func f() net.Addr {
 	e := &net.TCPAddr{IP: ip4, Port: p}
 	return &net.TCPAddr{IP: ip5}, nil
 }
+`,
+	},
+	{
+		// A local variable named net shadows the import, so the
+		// composite literal here is not net.IPAddr and must be left
+		// alone.
+		Name: "netipv6zone.shadow",
+		In: `package main
+
+import "net"
+
+func f() {
+	net := 5
+	d := &net.IPAddr{ip6}
+	_ = net
+	_ = d
+}
+`,
+		Out: `package main
+
+import "net"
+
+func f() {
+	net := 5
+	d := &net.IPAddr{ip6}
+	_ = net
+	_ = d
+}
 `,
 	},
 }
@@ -0,0 +1,106 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/tmc/fix"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	fix.Register(netparseipzeroFix)
+}
+
+var netparseipzeroFix = fix.Fix{
+	Name: "netparseipzero",
+	Date: "2021-08-16",
+	F:    netparseipzero,
+	Desc: `Strip leading zeros from IPv4 octets in net.ParseIP, net.ParseCIDR,
+netip.ParseAddr, and netip.ParsePrefix string literals.
+
+Go 1.17 made these functions reject octets with leading zeros, so code
+written against 1.16 that parses a literal like "192.168.001.1" now
+fails outright instead of silently misparsing it.
+
+https://go.dev/doc/go1.17#net
+`,
+}
+
+// ipOctetLeadingZero matches an IPv4 octet with one or more redundant
+// leading zeros, capturing the digits that should remain. A bare "0"
+// has no redundant zero to strip and is intentionally left unmatched.
+var ipOctetLeadingZero = regexp.MustCompile(`^0+([0-9]+)$`)
+
+func netparseipzero(f *ast.File) bool {
+	if !fix.Imports(f, "net") && !fix.Imports(f, "net/netip") {
+		return false
+	}
+
+	fixed := false
+	fix.Walk(f, func(n interface{}) {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return
+		}
+		se, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || se.Sel == nil {
+			return
+		}
+		switch {
+		case fix.IsTopName(se.X, "net") && (se.Sel.Name == "ParseIP" || se.Sel.Name == "ParseCIDR"):
+		case fix.IsTopName(se.X, "netip") && (se.Sel.Name == "ParseAddr" || se.Sel.Name == "ParsePrefix"):
+		default:
+			return
+		}
+
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return
+		}
+		if norm, ok := normalizeIPLiteral(s); ok {
+			lit.Value = strconv.Quote(norm)
+			fixed = true
+		}
+	})
+	return fixed
+}
+
+// normalizeIPLiteral strips redundant leading zeros from the IPv4 octets
+// of s, leaving any zone or CIDR prefix-length suffix and all IPv6
+// literals untouched. It reports whether it changed anything.
+func normalizeIPLiteral(s string) (string, bool) {
+	addr, suffix := s, ""
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		addr, suffix = s[:i], s[i:]
+	}
+	if strings.Contains(addr, ":") {
+		return s, false
+	}
+
+	octets := strings.Split(addr, ".")
+	if len(octets) != 4 {
+		return s, false
+	}
+
+	changed := false
+	for i, o := range octets {
+		if m := ipOctetLeadingZero.FindStringSubmatch(o); m != nil {
+			octets[i] = m[1]
+			changed = true
+		}
+	}
+	if !changed {
+		return s, false
+	}
+	return strings.Join(octets, ".") + suffix, true
+}
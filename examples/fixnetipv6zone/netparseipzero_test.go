@@ -0,0 +1,91 @@
+// Copyright 2021 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+func init() {
+	addTestCases(netparseipzeroTests, netparseipzero)
+}
+
+var netparseipzeroTests = []testCase{
+	{
+		Name: "netparseipzero.0",
+		In: `package main
+
+import "net"
+
+var _ = net.ParseIP("01.02.03.04")
+`,
+		Out: `package main
+
+import "net"
+
+var _ = net.ParseIP("1.2.3.4")
+`,
+	},
+	{
+		Name: "netparseipzero.1",
+		In: `package main
+
+import "net"
+
+func f() {
+	_, _, _ = net.ParseCIDR("192.168.001.001/24")
+}
+`,
+		Out: `package main
+
+import "net"
+
+func f() {
+	_, _, _ = net.ParseCIDR("192.168.1.1/24")
+}
+`,
+	},
+	{
+		Name: "netparseipzero.2",
+		In: `package main
+
+import "net"
+
+var _ = net.ParseIP("::1")
+`,
+		Out: `package main
+
+import "net"
+
+var _ = net.ParseIP("::1")
+`,
+	},
+	{
+		Name: "netparseipzero.3",
+		In: `package main
+
+import "net"
+
+var _ = net.ParseIP("192.168.1.1")
+`,
+		Out: `package main
+
+import "net"
+
+var _ = net.ParseIP("192.168.1.1")
+`,
+	},
+	{
+		Name: "netparseipzero.4",
+		In: `package main
+
+import "net"
+
+var _ = net.ParseIP("10.0.0.00")
+`,
+		Out: `package main
+
+import "net"
+
+var _ = net.ParseIP("10.0.0.0")
+`,
+	},
+}
@@ -0,0 +1,202 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fix
+
+import (
+	"flag"
+	"fmt"
+	"go/parser"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Main implements the standard gofix command-line driver for the given
+// set of fixes: it parses flags, then reads files or directories named
+// on the command line (or standard input, if none are named), applies
+// the selected fixes, and writes the results back out.
+//
+// Main calls os.Exit and so does not return.
+func Main(fixes []Fix) {
+	fs := flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	allowedRewrites := fs.String("r", "",
+		"restrict the rewrites to this comma-separated list")
+	forceParse := fs.Bool("force", false,
+		"skip files that do not parse instead of reporting an error")
+	doDiff := fs.Bool("diff", false,
+		"display diffs instead of rewriting files")
+
+	fs.Usage = func() { usage(fs, fixes) }
+	fs.Parse(os.Args[1:])
+
+	sort.Sort(byDate(fixes))
+
+	var allowed map[string]bool
+	if *allowedRewrites != "" {
+		allowed = make(map[string]bool)
+		for _, name := range strings.Split(*allowedRewrites, ",") {
+			allowed[name] = true
+		}
+	}
+
+	d := &driver{
+		fixes:   fixes,
+		allowed: allowed,
+		force:   *forceParse,
+		doDiff:  *doDiff,
+	}
+
+	exitCode := 0
+	report := func(err error) {
+		fmt.Fprintln(os.Stderr, err)
+		exitCode = 2
+	}
+
+	if fs.NArg() == 0 {
+		if err := d.processFile("standard input", true); err != nil {
+			report(err)
+		}
+		os.Exit(exitCode)
+	}
+
+	for i := 0; i < fs.NArg(); i++ {
+		path := fs.Arg(i)
+		switch info, err := os.Stat(path); {
+		case err != nil:
+			report(err)
+		case info.IsDir():
+			d.walkDir(path, report)
+		default:
+			if err := d.processFile(path, false); err != nil {
+				report(err)
+			}
+		}
+	}
+	os.Exit(exitCode)
+}
+
+func usage(fs *flag.FlagSet, fixes []Fix) {
+	fmt.Fprintf(os.Stderr, "usage: %s [-diff] [-r fixname,...] [-force] [path ...]\n", os.Args[0])
+	fs.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nAvailable rewrites are:\n")
+	for _, f := range fixes {
+		desc := strings.TrimSpace(f.Desc)
+		desc = strings.ReplaceAll(desc, "\n", "\n\t")
+		fmt.Fprintf(os.Stderr, "\n%s\n\t%s\n", f.Name, desc)
+	}
+}
+
+// A driver applies a set of fixes to files named on disk or read from
+// standard input.
+type driver struct {
+	fixes   []Fix
+	allowed map[string]bool
+	force   bool // skip, without an error, a file that fails to parse
+	doDiff  bool
+}
+
+func (d *driver) walkDir(root string, report func(error)) {
+	filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			report(err)
+			return nil
+		}
+		if e.IsDir() {
+			switch name := e.Name(); {
+			case name == "testdata" || name == "vendor":
+				return filepath.SkipDir
+			case name != "." && strings.HasPrefix(name, "."):
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		if err := d.processFile(path, false); err != nil {
+			report(err)
+		}
+		return nil
+	})
+}
+
+func (d *driver) processFile(filename string, useStdin bool) error {
+	var in io.Reader = os.Stdin
+	if !useStdin {
+		f, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	file, err := parser.ParseFile(FileSet, filename, src, ParserMode)
+	if err != nil {
+		if d.force {
+			// An unparseable file is skipped rather than
+			// reported: no fix is applied to it, and standard
+			// input is copied to standard output unchanged.
+			if useStdin {
+				_, err := os.Stdout.Write(src)
+				return err
+			}
+			return nil
+		}
+		return err
+	}
+
+	fixed := false
+	var applied []string
+	for _, fix := range d.fixes {
+		if d.allowed != nil && !d.allowed[fix.Name] {
+			continue
+		}
+		if fix.F(file) {
+			fixed = true
+			applied = append(applied, fix.Name)
+		}
+	}
+	if !fixed {
+		if useStdin {
+			_, err := os.Stdout.Write(src)
+			return err
+		}
+		return nil
+	}
+
+	newSrc, err := GofmtFile(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s: fixed %s\n", filename, strings.Join(applied, ", "))
+
+	if d.doDiff {
+		data, err := Diff(src, newSrc)
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(data)
+		return nil
+	}
+
+	if useStdin {
+		_, err := os.Stdout.Write(newSrc)
+		return err
+	}
+
+	return ioutil.WriteFile(filename, newSrc, 0)
+}
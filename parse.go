@@ -0,0 +1,69 @@
+// Copyright 2011 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fix
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// FileSet is the file set used to parse and print files passed to fixes.
+var FileSet = token.NewFileSet()
+
+// ParserMode is the mode used to parse files passed to fixes.
+const ParserMode = parser.ParseComments
+
+// GofmtFile returns the gofmt-formatted source for f.
+func GofmtFile(f *ast.File) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, FileSet, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Diff returns the output of running diff on b1 and b2.
+func Diff(b1, b2 []byte) (data []byte, err error) {
+	f1, err := writeTempFile("", "fix", b1)
+	if err != nil {
+		return
+	}
+	defer os.Remove(f1)
+
+	f2, err := writeTempFile("", "fix", b2)
+	if err != nil {
+		return
+	}
+	defer os.Remove(f2)
+
+	data, err = exec.Command("diff", "-u", f1, f2).CombinedOutput()
+	if len(data) > 0 {
+		// diff exits with a non-zero status when the files differ; that's not an error.
+		err = nil
+	}
+	return
+}
+
+func writeTempFile(dir, prefix string, data []byte) (string, error) {
+	file, err := ioutil.TempFile(dir, prefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write(data)
+	if err1 := file.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fix
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+)
+
+const addressOfOp = token.AND
+
+// PackageObject returns a synthetic Object representing the package
+// qualifier used to refer to the import of importPath in f, accounting
+// for a renamed import, or nil if f does not import importPath.
+//
+// Unlike a declared variable or parameter, an import's package name is
+// never assigned a real ast.Object by go/parser, so RefersTo and its
+// callers treat an Object with Kind == ast.Pkg as this toy typechecker's
+// stand-in for "the package name introduced by this import", rather
+// than an object to compare by identity.
+func PackageObject(f *ast.File, importPath string) *ast.Object {
+	spec := importSpec(f, importPath)
+	if spec == nil {
+		return nil
+	}
+	name := ""
+	if spec.Name != nil {
+		name = spec.Name.Name
+	} else {
+		_, name = path.Split(importPath)
+	}
+	return &ast.Object{Kind: ast.Pkg, Name: name}
+}
+
+// RefersTo reports whether id refers to obj.
+//
+// For a declared object (a parameter, local variable, or top-level
+// declaration), this is exactly the identity comparison go/parser
+// already performs during scope resolution: id.Obj == obj.
+//
+// For obj returned by PackageObject, id refers to it only if go/parser
+// left id unresolved -- i.e. no local declaration of the same name
+// shadows the import -- and the names match. This is the fallback toy
+// typecheck: it lets callers ask "is this the net package" without
+// mistaking an identically-named local variable for the import.
+func RefersTo(f *ast.File, id *ast.Ident, obj *ast.Object) bool {
+	if obj == nil {
+		return false
+	}
+	if obj.Kind == ast.Pkg {
+		return id.Obj == nil && id.Name == obj.Name
+	}
+	return id.Obj == obj
+}
+
+// RewriteUses walks f, replacing every expression that is a direct
+// reference to obj with the result of calling replace on it.
+func RewriteUses(f *ast.File, obj *ast.Object, replace func(*ast.Ident) ast.Expr) {
+	Walk(f, func(n interface{}) {
+		ep, ok := n.(*ast.Expr)
+		if !ok {
+			return
+		}
+		id, ok := (*ep).(*ast.Ident)
+		if !ok || !RefersTo(f, id, obj) {
+			return
+		}
+		*ep = replace(id)
+	})
+}
+
+// AssignsTo reports whether f contains a statement that assigns to obj:
+// a plain assignment, an increment or decrement, or taking its address.
+func AssignsTo(f *ast.File, obj *ast.Object) bool {
+	assigns := false
+	Walk(f, func(n interface{}) {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && RefersTo(f, id, obj) {
+					// The := that declares obj (x := 1) is not an
+					// assignment to it; a later := that redeclares
+					// obj (x, err := g()) is.
+					if n.Tok == token.DEFINE && obj.Decl == n {
+						continue
+					}
+					assigns = true
+				}
+			}
+		case *ast.IncDecStmt:
+			if id, ok := n.X.(*ast.Ident); ok && RefersTo(f, id, obj) {
+				assigns = true
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.ASSIGN {
+				if id, ok := n.Key.(*ast.Ident); ok && RefersTo(f, id, obj) {
+					assigns = true
+				}
+				if id, ok := n.Value.(*ast.Ident); ok && RefersTo(f, id, obj) {
+					assigns = true
+				}
+			}
+		case *ast.UnaryExpr:
+			if id, ok := n.X.(*ast.Ident); ok && n.Op == addressOfOp && RefersTo(f, id, obj) {
+				assigns = true
+			}
+		}
+	})
+	return assigns
+}
+
+// CountUses returns the number of identifiers in f that refer to obj.
+func CountUses(f *ast.File, obj *ast.Object) int {
+	n := 0
+	Walk(f, func(x interface{}) {
+		if id, ok := x.(*ast.Ident); ok && RefersTo(f, id, obj) {
+			n++
+		}
+	})
+	return n
+}
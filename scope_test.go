@@ -0,0 +1,140 @@
+// Copyright 2012 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fix
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// findObject returns the Object of the first identifier named name
+// encountered in a depth-first walk of f, which for these tests is
+// always its declaring identifier.
+func findObject(f *ast.File, name string) *ast.Object {
+	var obj *ast.Object
+	ast.Inspect(f, func(n ast.Node) bool {
+		if obj != nil {
+			return false
+		}
+		if id, ok := n.(*ast.Ident); ok && id.Name == name && id.Obj != nil {
+			obj = id.Obj
+			return false
+		}
+		return true
+	})
+	return obj
+}
+
+func parseSnippet(t *testing.T, src string) *ast.File {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "t.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+	return f
+}
+
+func TestAssignsToRangeClause(t *testing.T) {
+	// k, v already declared: "for k, v = range m" assigns to the
+	// existing k.
+	assign := parseSnippet(t, `package p
+
+func f(m map[string]int) {
+	var k string
+	var v int
+	for k, v = range m {
+		_ = k
+		_ = v
+	}
+}
+`)
+	if k := findObject(assign, "k"); !AssignsTo(assign, k) {
+		t.Errorf("AssignsTo(k) = false, want true for range clause using =")
+	}
+
+	// "for k, v := range m" declares a new k that shadows the
+	// outer one, so the outer k is never assigned to.
+	define := parseSnippet(t, `package p
+
+func f(m map[string]int) {
+	k := 5
+	for k, v := range m {
+		_ = k
+		_ = v
+	}
+	_ = k
+}
+`)
+	if k := findObject(define, "k"); AssignsTo(define, k) {
+		t.Errorf("AssignsTo(k) = true, want false: range clause using := declares its own k")
+	}
+}
+
+func TestAssignsToAddressOf(t *testing.T) {
+	f := parseSnippet(t, `package p
+
+func f() {
+	var x int
+	p := &x
+	_ = p
+}
+`)
+	if x := findObject(f, "x"); !AssignsTo(f, x) {
+		t.Errorf("AssignsTo(x) = false, want true: &x takes x's address")
+	}
+}
+
+func TestCountUses(t *testing.T) {
+	f := parseSnippet(t, `package p
+
+func f() int {
+	x := 1
+	y := x + x
+	return y
+}
+`)
+	x := findObject(f, "x")
+	if n := CountUses(f, x); n != 3 {
+		t.Errorf("CountUses(x) = %d, want 3 (the declaration plus two reads)", n)
+	}
+}
+
+func TestRewriteUses(t *testing.T) {
+	f := parseSnippet(t, `package p
+
+func f() int {
+	x := 1
+	return x + x
+}
+`)
+	x := findObject(f, "x")
+	replaced := 0
+	RewriteUses(f, x, func(id *ast.Ident) ast.Expr {
+		replaced++
+		if replaced == 1 {
+			// Leave the declaration's own identifier alone.
+			return id
+		}
+		return ast.NewIdent("y")
+	})
+
+	out, err := GofmtFile(f)
+	if err != nil {
+		t.Fatalf("formatting: %v", err)
+	}
+
+	want := `package p
+
+func f() int {
+	x := 1
+	return y + y
+}
+`
+	if string(out) != want {
+		t.Errorf("RewriteUses produced:\n%s\nwant:\n%s", out, want)
+	}
+}